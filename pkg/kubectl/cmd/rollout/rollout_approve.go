@@ -0,0 +1,221 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/kubernetes/pkg/kubectl/cmd/set"
+	"k8s.io/kubernetes/pkg/kubectl/cmd/templates"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions"
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+	"k8s.io/kubernetes/pkg/kubectl/polymorphichelpers"
+	"k8s.io/kubernetes/pkg/kubectl/scheme"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/printers"
+)
+
+// ApproveConfig is the start of the data required to perform the operation.  As new fields are added, add them here instead of
+// referencing the cmd.Flags()
+type ApproveConfig struct {
+	resource.FilenameOptions
+	PrintFlags *printers.PrintFlags
+	ToPrinter  func(string) (printers.ResourcePrinterFunc, error)
+
+	// Batch advances the rollout to a specific batch index. A negative value
+	// means no specific batch was requested.
+	Batch int
+	// All advances the rollout through every remaining batch.
+	All bool
+
+	Approver func(object *resource.Info, batch int, all bool) ([]byte, error)
+	Infos    []*resource.Info
+
+	genericclioptions.IOStreams
+}
+
+var (
+	approve_long = templates.LongDesc(`
+		Approve a resource waiting at a canary or blue-green gate
+
+		Resources paused at a gated rollout step will not advance to their next
+		batch until approved. By approving a resource, we let it proceed to the
+		next batch, or, with --all, through every remaining batch.`)
+
+	approve_example = templates.Examples(`
+		# Approve the next batch of a Kruise Rollout
+		kubectl rollout approve rollout/nginx
+
+		# Approve a specific batch of a Kruise Rollout
+		kubectl rollout approve rollout/nginx --batch=3
+
+		# Approve all the remaining batches of a Kruise Rollout
+		kubectl rollout approve rollout/nginx --all`)
+)
+
+// NewCmdRolloutApprove returns a Command instance for 'rollout approve' sub command
+func NewCmdRolloutApprove(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ApproveConfig{
+		PrintFlags: printers.NewPrintFlags("approved").WithTypeSetter(scheme.Scheme),
+		IOStreams:  streams,
+		Batch:      -1,
+	}
+
+	validArgs := []string{"deployment", "rollout"}
+
+	cmd := &cobra.Command{
+		Use: "approve RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:   i18n.T("Approve a gated rollout step"),
+		Long:    approve_long,
+		Example: approve_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			allErrs := []error{}
+			err := o.CompleteApprove(f, cmd, args)
+			if err != nil {
+				allErrs = append(allErrs, err)
+			}
+			err = o.RunApprove()
+			if err != nil {
+				allErrs = append(allErrs, err)
+			}
+			cmdutil.CheckErr(utilerrors.Flatten(utilerrors.NewAggregate(allErrs)))
+		},
+		ValidArgs: validArgs,
+	}
+
+	cmd.Flags().IntVar(&o.Batch, "batch", o.Batch, "Advance the rollout to this batch index. Defaults to the next pending batch.")
+	cmd.Flags().BoolVar(&o.All, "all", o.All, "Approve all of the remaining batches.")
+	usage := "identifying the resource to get from a server."
+	cmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, usage)
+	return cmd
+}
+
+// CompleteApprove verifies command line arguments and loads data from the command environment
+func (o *ApproveConfig) CompleteApprove(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && cmdutil.IsFilenameSliceEmpty(o.Filenames) {
+		return cmdutil.UsageErrorf(cmd, "%s", cmd.Use)
+	}
+
+	if o.Batch >= 0 && o.All {
+		return cmdutil.UsageErrorf(cmd, "--batch and --all are mutually exclusive")
+	}
+
+	o.Approver = polymorphichelpers.ObjectApproverFn
+
+	cmdNamespace, enforceNamespace, err := f.DefaultNamespace()
+	if err != nil {
+		return err
+	}
+
+	o.ToPrinter = func(operation string) (printers.ResourcePrinterFunc, error) {
+		o.PrintFlags.NamePrintFlags.Operation = operation
+		printer, err := o.PrintFlags.ToPrinter()
+		if err != nil {
+			return nil, err
+		}
+
+		return printer.PrintObj, nil
+	}
+
+	r := f.NewBuilder().
+		WithScheme(legacyscheme.Scheme).
+		NamespaceParam(cmdNamespace).DefaultNamespace().
+		FilenameParam(enforceNamespace, &o.FilenameOptions).
+		ResourceTypeOrNameArgs(true, args...).
+		ContinueOnError().
+		Latest().
+		Flatten().
+		Do()
+	err = r.Err()
+	if err != nil {
+		return err
+	}
+
+	err = r.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+		o.Infos = append(o.Infos, info)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RunApprove performs the execution of 'rollout approve' sub command
+func (o ApproveConfig) RunApprove() error {
+	allErrs := []error{}
+	patchFn := func(info *resource.Info) ([]byte, error) {
+		return o.Approver(info, o.Batch, o.All)
+	}
+	for _, patch := range set.CalculatePatches(o.Infos, cmdutil.InternalVersionJSONEncoder(), patchFn) {
+		info := patch.Info
+
+		if patch.Err != nil {
+			resourceString := info.Mapping.Resource.Resource
+			if len(info.Mapping.Resource.Group) > 0 {
+				resourceString = resourceString + "." + info.Mapping.Resource.Group
+			}
+			allErrs = append(allErrs, fmt.Errorf("error: %s %q %v", resourceString, info.Name, patch.Err))
+			continue
+		}
+
+		if string(patch.Patch) == "{}" || len(patch.Patch) == 0 {
+			printer, err := o.ToPrinter("already approved")
+			if err != nil {
+				allErrs = append(allErrs, err)
+				continue
+			}
+			printer.PrintObj(cmdutil.AsDefaultVersionedOrOriginal(info.Object, info.Mapping), o.Out)
+			continue
+		}
+
+		patchType, subresource, err := polymorphichelpers.ApprovePatchType(info.Object)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			continue
+		}
+		var subresources []string
+		if subresource != "" {
+			subresources = []string{subresource}
+		}
+
+		obj, err := resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, patchType, patch.Patch, subresources...)
+		if err != nil {
+			allErrs = append(allErrs, fmt.Errorf("failed to patch: %v", err))
+			continue
+		}
+
+		info.Refresh(obj, true)
+		printer, err := o.ToPrinter("approved")
+		if err != nil {
+			allErrs = append(allErrs, err)
+			continue
+		}
+		printer.PrintObj(cmdutil.AsDefaultVersionedOrOriginal(info.Object, info.Mapping), o.Out)
+	}
+
+	return utilerrors.NewAggregate(allErrs)
+}
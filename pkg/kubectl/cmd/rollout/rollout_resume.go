@@ -17,21 +17,40 @@ limitations under the License.
 package rollout
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/kubernetes/pkg/api/legacyscheme"
 	"k8s.io/kubernetes/pkg/kubectl/cmd/set"
 	"k8s.io/kubernetes/pkg/kubectl/cmd/templates"
 	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
 	"k8s.io/kubernetes/pkg/kubectl/genericclioptions"
 	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+	"k8s.io/kubernetes/pkg/kubectl/polymorphichelpers"
 	"k8s.io/kubernetes/pkg/kubectl/scheme"
 	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
 	"k8s.io/kubernetes/pkg/printers"
+	"sigs.k8s.io/yaml"
+)
+
+// Dry-run strategies accepted by --dry-run, matching the rest of kubectl.
+const (
+	cmdDryRunNone   = "none"
+	cmdDryRunClient = "client"
+	cmdDryRunServer = "server"
 )
 
 // ResumeConfig is the start of the data required to perform the operation.  As new fields are added, add them here instead of
@@ -41,7 +60,19 @@ type ResumeConfig struct {
 	PrintFlags *printers.PrintFlags
 	ToPrinter  func(string) (printers.ResourcePrinterFunc, error)
 
-	Resumer func(object *resource.Info) ([]byte, error)
+	// Full indicates that, in addition to clearing the pause, any
+	// partition-based hold on the rollout should also be released.
+	Full bool
+
+	// Wait, when true, blocks after a successful patch until the rollout
+	// reports ready or Timeout elapses.
+	Wait    bool
+	Timeout time.Duration
+
+	// DryRunStrategy is one of cmdDryRunNone, cmdDryRunClient, or cmdDryRunServer.
+	DryRunStrategy string
+
+	Resumer func(object *resource.Info, full bool) ([]byte, error)
 	Infos   []*resource.Info
 
 	genericclioptions.IOStreams
@@ -53,11 +84,21 @@ var (
 
 		Paused resources will not be reconciled by a controller. By resuming a
 		resource, we allow it to be reconciled again.
-		Currently only deployments support being resumed.`)
+		Deployments, StatefulSets, DaemonSets, and the Kruise CloneSet and
+		Advanced StatefulSet workloads support being resumed.`)
 
 	resume_example = templates.Examples(`
 		# Resume an already paused deployment
-		kubectl rollout resume deployment/nginx`)
+		kubectl rollout resume deployment/nginx
+
+		# Resume a partitioned statefulset and clear its partition hold
+		kubectl rollout resume statefulset/web --full
+
+		# Resume a deployment and wait for the rollout to finish
+		kubectl rollout resume deployment/nginx --wait --timeout=2m
+
+		# Preview the resume patch without persisting it
+		kubectl rollout resume deployment/nginx --dry-run=server -o diff`)
 )
 
 func NewCmdRolloutResume(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
@@ -66,7 +107,7 @@ func NewCmdRolloutResume(f cmdutil.Factory, streams genericclioptions.IOStreams)
 		IOStreams:  streams,
 	}
 
-	validArgs := []string{"deployment"}
+	validArgs := []string{"deployment", "statefulset", "daemonset", "cloneset", "advancedstatefulset"}
 
 	cmd := &cobra.Command{
 		Use: "resume RESOURCE",
@@ -89,6 +130,11 @@ func NewCmdRolloutResume(f cmdutil.Factory, streams genericclioptions.IOStreams)
 		ValidArgs: validArgs,
 	}
 
+	cmd.Flags().BoolVar(&o.Full, "full", o.Full, "Clear any partition-based hold on the rollout in addition to resuming it.")
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "Wait for the resumed rollout to finish before returning.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait for the rollout to finish, zero means wait forever. Only applies with --wait.")
+	cmd.Flags().StringVar(&o.DryRunStrategy, "dry-run", cmdDryRunNone, `Must be "none", "client", or "server". If "client", only print the object that would be sent, without sending it. If "server", submit the patch to the server with a server-side dry run, without persisting the change.`)
+	o.PrintFlags.AddFlags(cmd)
 	usage := "identifying the resource to get from a server."
 	cmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, usage)
 	return cmd
@@ -99,7 +145,13 @@ func (o *ResumeConfig) CompleteResume(f cmdutil.Factory, cmd *cobra.Command, arg
 		return cmdutil.UsageErrorf(cmd, "%s", cmd.Use)
 	}
 
-	o.Resumer = f.Resumer
+	switch o.DryRunStrategy {
+	case cmdDryRunNone, cmdDryRunClient, cmdDryRunServer:
+	default:
+		return cmdutil.UsageErrorf(cmd, "invalid --dry-run value %q: must be %q, %q, or %q", o.DryRunStrategy, cmdDryRunNone, cmdDryRunClient, cmdDryRunServer)
+	}
+
+	o.Resumer = polymorphichelpers.ObjectResumerFn
 
 	cmdNamespace, enforceNamespace, err := f.DefaultNamespace()
 	if err != nil {
@@ -145,7 +197,12 @@ func (o *ResumeConfig) CompleteResume(f cmdutil.Factory, cmd *cobra.Command, arg
 
 func (o ResumeConfig) RunResume() error {
 	allErrs := []error{}
-	for _, patch := range set.CalculatePatches(o.Infos, cmdutil.InternalVersionJSONEncoder(), o.Resumer) {
+	isDiffOutput := o.PrintFlags.OutputFormat != nil && *o.PrintFlags.OutputFormat == "diff"
+
+	patchFn := func(info *resource.Info) ([]byte, error) {
+		return o.Resumer(info, o.Full)
+	}
+	for _, patch := range set.CalculatePatches(o.Infos, cmdutil.InternalVersionJSONEncoder(), patchFn) {
 		info := patch.Info
 
 		if patch.Err != nil {
@@ -158,28 +215,303 @@ func (o ResumeConfig) RunResume() error {
 		}
 
 		if string(patch.Patch) == "{}" || len(patch.Patch) == 0 {
-			printer, err := o.ToPrinter("already resumed")
+			if isDiffOutput {
+				continue
+			}
+			if err := o.printResumeResult(info, info.Object, "already resumed"); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			continue
+		}
+
+		patchType, err := polymorphichelpers.ResumePatchType(info.Object)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			continue
+		}
+
+		// --dry-run=client and -o diff never persist the patch: preview the
+		// patched object, locally against the object we already fetched, or
+		// through a server dry run when --dry-run=server is also set.
+		if isDiffOutput || o.DryRunStrategy == cmdDryRunClient {
+			previewed, err := o.previewPatchedObject(info, patch.Patch, patchType)
 			if err != nil {
 				allErrs = append(allErrs, err)
 				continue
 			}
-			printer.PrintObj(cmdutil.AsDefaultVersionedOrOriginal(info.Object, info.Mapping), o.Out)
+			if isDiffOutput {
+				if err := printResumeDiff(info.Object, previewed, o.Out); err != nil {
+					allErrs = append(allErrs, err)
+				}
+				continue
+			}
+			if err := o.printResumeResult(info, previewed, "resumed (dry run)"); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			continue
 		}
 
-		obj, err := resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, types.StrategicMergePatchType, patch.Patch)
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		if o.DryRunStrategy == cmdDryRunServer {
+			helper = helper.DryRun(true)
+		}
+		obj, err := helper.Patch(info.Namespace, info.Name, patchType, patch.Patch)
 		if err != nil {
 			allErrs = append(allErrs, fmt.Errorf("failed to patch: %v", err))
 			continue
 		}
 
+		operation := "resumed"
+		if o.DryRunStrategy == cmdDryRunServer {
+			operation = "resumed (server dry run)"
+		}
 		info.Refresh(obj, true)
-		printer, err := o.ToPrinter("resumed")
-		if err != nil {
+		if err := o.printResumeResult(info, info.Object, operation); err != nil {
 			allErrs = append(allErrs, err)
 			continue
 		}
-		printer.PrintObj(cmdutil.AsDefaultVersionedOrOriginal(info.Object, info.Mapping), o.Out)
+
+		if o.DryRunStrategy == cmdDryRunNone {
+			if err := o.waitForRolloutComplete(info); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
 	}
 
 	return utilerrors.NewAggregate(allErrs)
 }
+
+// previewPatchedObject returns what info's object would look like after
+// patch is applied, without persisting anything: for --dry-run=server it
+// asks the API server to validate (and return) the patched object; otherwise
+// it applies the merge patch locally against the object already in hand.
+func (o ResumeConfig) previewPatchedObject(info *resource.Info, patch []byte, patchType types.PatchType) (runtime.Object, error) {
+	if o.DryRunStrategy == cmdDryRunServer {
+		helper := resource.NewHelper(info.Client, info.Mapping).DryRun(true)
+		return helper.Patch(info.Namespace, info.Name, patchType, patch)
+	}
+	return applyMergePatchLocally(info.Object, patch)
+}
+
+// applyMergePatchLocally merges patch into original without any server
+// round trip. It is sufficient for the flat JSON merge patches the rollout
+// resumers emit, which never touch list fields.
+func applyMergePatchLocally(original runtime.Object, patch []byte) (runtime.Object, error) {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return nil, err
+	}
+	mergedJSON, err := jsonpatch.MergePatch(originalJSON, patch)
+	if err != nil {
+		return nil, err
+	}
+	patched := original.DeepCopyObject()
+	if err := json.Unmarshal(mergedJSON, patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// printResumeResult prints obj using the name/output printer configured via
+// PrintFlags, labeled with operation (e.g. "resumed", "already resumed").
+func (o ResumeConfig) printResumeResult(info *resource.Info, obj runtime.Object, operation string) error {
+	printer, err := o.ToPrinter(operation)
+	if err != nil {
+		return err
+	}
+	return printer.PrintObj(cmdutil.AsDefaultVersionedOrOriginal(obj, info.Mapping), o.Out)
+}
+
+// printResumeDiff writes a unified diff between the pre-patch and post-patch
+// (server dry-run, or locally previewed) objects to out, rendering each as
+// YAML so the diff reads the way the rest of kubectl's -o diff output does.
+func printResumeDiff(original, patched runtime.Object, out io.Writer) error {
+	originalYAML, err := yaml.Marshal(original)
+	if err != nil {
+		return err
+	}
+	patchedYAML, err := yaml.Marshal(patched)
+	if err != nil {
+		return err
+	}
+
+	diffString, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(originalYAML)),
+		B:        difflib.SplitLines(string(patchedYAML)),
+		FromFile: "before",
+		ToFile:   "after",
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, diffString)
+	return nil
+}
+
+// waitForRolloutComplete polls the resumed resource's status until it reports
+// a finished rollout or o.Timeout elapses. It is a no-op unless --wait was set.
+func (o ResumeConfig) waitForRolloutComplete(info *resource.Info) error {
+	if !o.Wait {
+		return nil
+	}
+
+	accessor, err := meta.Accessor(info.Object)
+	if err != nil {
+		return err
+	}
+	desiredGeneration := accessor.GetGeneration()
+
+	var lastMessage string
+	report := func(message string) {
+		if message != "" && message != lastMessage {
+			fmt.Fprintln(o.Out, message)
+			lastMessage = message
+		}
+	}
+
+	// The object we already have may already be done rolling out (e.g. it
+	// was already complete when we patched it, or the patch was a no-op),
+	// in which case the watch below would never see another event and,
+	// with the default --timeout=0, would block forever.
+	done, message, err := rolloutCompleteForObject(info.Object, desiredGeneration)
+	if err != nil {
+		return err
+	}
+	report(message)
+	if done {
+		return nil
+	}
+
+	w, err := resource.NewHelper(info.Client, info.Mapping).WatchSingle(info.Namespace, info.Name, accessor.GetResourceVersion())
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	var timeoutCh <-chan time.Time
+	if o.Timeout > 0 {
+		timeoutCh = time.After(o.Timeout)
+	}
+
+	for {
+		select {
+		case <-timeoutCh:
+			return fmt.Errorf("timed out waiting for %q rollout to finish", info.Name)
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed before %q rollout finished", info.Name)
+			}
+			if event.Type == watch.Error {
+				return fmt.Errorf("error watching %q: %v", info.Name, event.Object)
+			}
+
+			done, message, err := rolloutCompleteForObject(event.Object, desiredGeneration)
+			if err != nil {
+				return err
+			}
+			report(message)
+			if done {
+				return nil
+			}
+		}
+	}
+}
+
+// rolloutCompleteForObject converts obj to unstructured and reports whether
+// it has finished rolling out to desiredGeneration, dispatching on its kind.
+func rolloutCompleteForObject(obj runtime.Object, desiredGeneration int64) (done bool, message string, err error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false, "", err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	return rolloutCompleteFuncFor(u.GetKind())(u, desiredGeneration)
+}
+
+// rolloutCompleteFunc reports whether obj has finished rolling out to
+// desiredGeneration, along with a human-readable progress message.
+type rolloutCompleteFunc func(obj *unstructured.Unstructured, desiredGeneration int64) (done bool, message string, err error)
+
+// rolloutCompleteFuncs allows the completion check to be tailored per kind.
+// Kinds not listed here fall back to replicaSetRolloutComplete, which covers
+// any workload that reports status.{observedGeneration,updatedReplicas,availableReplicas}
+// alongside spec.replicas (Deployments, StatefulSets, and similar).
+var rolloutCompleteFuncs = map[string]rolloutCompleteFunc{
+	"DaemonSet": daemonSetRolloutComplete,
+}
+
+func rolloutCompleteFuncFor(kind string) rolloutCompleteFunc {
+	if fn, ok := rolloutCompleteFuncs[kind]; ok {
+		return fn
+	}
+	return replicaSetRolloutComplete
+}
+
+func replicaSetRolloutComplete(obj *unstructured.Unstructured, desiredGeneration int64) (bool, string, error) {
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, "", err
+	}
+	if !found || observedGeneration < desiredGeneration {
+		return false, "", nil
+	}
+
+	replicas, _, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil {
+		return false, "", err
+	}
+	updatedReplicas, _, err := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	if err != nil {
+		return false, "", err
+	}
+	availableReplicas, _, err := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+	if err != nil {
+		return false, "", err
+	}
+
+	kind := strings.ToLower(obj.GetKind())
+	name := obj.GetName()
+	if updatedReplicas < replicas {
+		return false, fmt.Sprintf("Waiting for %s %q rollout to finish: %d of %d replicas have been updated...", kind, name, updatedReplicas, replicas), nil
+	}
+	if availableReplicas < replicas {
+		return false, fmt.Sprintf("Waiting for %s %q rollout to finish: %d of %d updated replicas are available...", kind, name, availableReplicas, replicas), nil
+	}
+	return true, fmt.Sprintf("%s %q successfully rolled out", kind, name), nil
+}
+
+// daemonSetRolloutComplete checks readiness the DaemonSet way: DaemonSets
+// have no spec.replicas, so completion is judged against
+// status.desiredNumberScheduled instead.
+func daemonSetRolloutComplete(obj *unstructured.Unstructured, desiredGeneration int64) (bool, string, error) {
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, "", err
+	}
+	if !found || observedGeneration < desiredGeneration {
+		return false, "", nil
+	}
+
+	desiredNumberScheduled, _, err := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	if err != nil {
+		return false, "", err
+	}
+	updatedNumberScheduled, _, err := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+	if err != nil {
+		return false, "", err
+	}
+	numberAvailable, _, err := unstructured.NestedInt64(obj.Object, "status", "numberAvailable")
+	if err != nil {
+		return false, "", err
+	}
+
+	name := obj.GetName()
+	if updatedNumberScheduled < desiredNumberScheduled {
+		return false, fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d of %d replicas have been updated...", name, updatedNumberScheduled, desiredNumberScheduled), nil
+	}
+	if numberAvailable < desiredNumberScheduled {
+		return false, fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d of %d updated replicas are available...", name, numberAvailable, desiredNumberScheduled), nil
+	}
+	return true, fmt.Sprintf("daemon set %q successfully rolled out", name), nil
+}
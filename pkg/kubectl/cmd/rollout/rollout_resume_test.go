@@ -0,0 +1,217 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestUnstructured(kind string, status map[string]interface{}, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "test", "generation": int64(2)},
+		"spec":       spec,
+		"status":     status,
+	}}
+}
+
+func TestReplicaSetRolloutComplete(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        map[string]interface{}
+		spec          map[string]interface{}
+		desiredGen    int64
+		wantDone      bool
+		wantNoMessage bool
+	}{
+		{
+			name:          "observedGeneration behind desired generation waits silently",
+			status:        map[string]interface{}{"observedGeneration": int64(1)},
+			spec:          map[string]interface{}{"replicas": int64(3)},
+			desiredGen:    2,
+			wantDone:      false,
+			wantNoMessage: true,
+		},
+		{
+			name: "still updating replicas is not done",
+			status: map[string]interface{}{
+				"observedGeneration": int64(2), "updatedReplicas": int64(1), "availableReplicas": int64(1),
+			},
+			spec:       map[string]interface{}{"replicas": int64(3)},
+			desiredGen: 2,
+			wantDone:   false,
+		},
+		{
+			name: "updated but not yet available is not done",
+			status: map[string]interface{}{
+				"observedGeneration": int64(2), "updatedReplicas": int64(3), "availableReplicas": int64(1),
+			},
+			spec:       map[string]interface{}{"replicas": int64(3)},
+			desiredGen: 2,
+			wantDone:   false,
+		},
+		{
+			name: "fully updated and available is done",
+			status: map[string]interface{}{
+				"observedGeneration": int64(2), "updatedReplicas": int64(3), "availableReplicas": int64(3),
+			},
+			spec:       map[string]interface{}{"replicas": int64(3)},
+			desiredGen: 2,
+			wantDone:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newTestUnstructured("Deployment", tt.status, tt.spec)
+			done, message, err := replicaSetRolloutComplete(obj, tt.desiredGen)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if done != tt.wantDone {
+				t.Fatalf("done = %v, want %v (message: %q)", done, tt.wantDone, message)
+			}
+			if tt.wantNoMessage && message != "" {
+				t.Fatalf("expected no progress message, got %q", message)
+			}
+		})
+	}
+}
+
+// TestDaemonSetRolloutCompleteIgnoresSpecReplicas guards against treating a
+// DaemonSet's absent spec.replicas as zero desired replicas, which would
+// make --wait report success immediately instead of waiting for the nodes
+// to actually roll out.
+func TestDaemonSetRolloutCompleteIgnoresSpecReplicas(t *testing.T) {
+	obj := newTestUnstructured("DaemonSet", map[string]interface{}{
+		"observedGeneration":     int64(2),
+		"desiredNumberScheduled": int64(5),
+		"updatedNumberScheduled": int64(2),
+		"numberAvailable":        int64(2),
+	}, nil)
+
+	done, message, err := daemonSetRolloutComplete(obj, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done {
+		t.Fatalf("expected daemonset with 2/5 nodes updated to not be done, got message %q", message)
+	}
+
+	obj = newTestUnstructured("DaemonSet", map[string]interface{}{
+		"observedGeneration":     int64(2),
+		"desiredNumberScheduled": int64(5),
+		"updatedNumberScheduled": int64(5),
+		"numberAvailable":        int64(5),
+	}, nil)
+	done, _, err = daemonSetRolloutComplete(obj, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !done {
+		t.Fatalf("expected fully-updated daemonset to be done")
+	}
+}
+
+func TestRolloutCompleteFuncForDispatchesByKind(t *testing.T) {
+	if rolloutCompleteFuncFor("DaemonSet") == nil {
+		t.Fatalf("expected a DaemonSet-specific completion func to be registered")
+	}
+
+	// A DaemonSet with no spec.replicas must not be routed through the
+	// replica-based fallback, or it would report done immediately.
+	obj := newTestUnstructured("DaemonSet", map[string]interface{}{
+		"observedGeneration":     int64(2),
+		"desiredNumberScheduled": int64(5),
+		"updatedNumberScheduled": int64(1),
+		"numberAvailable":        int64(1),
+	}, nil)
+	done, _, err := rolloutCompleteFuncFor("DaemonSet")(obj, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done {
+		t.Fatalf("DaemonSet dispatch incorrectly reported rollout complete")
+	}
+}
+
+func TestApplyMergePatchLocally(t *testing.T) {
+	original := newTestUnstructured("Deployment", nil, map[string]interface{}{"paused": true, "replicas": int64(3)})
+	patched, err := applyMergePatchLocally(original, []byte(`{"spec":{"paused":false}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	patchedUnstructured, ok := patched.(*unstructured.Unstructured)
+	if !ok {
+		t.Fatalf("expected *unstructured.Unstructured, got %T", patched)
+	}
+	paused, _, _ := unstructured.NestedBool(patchedUnstructured.Object, "spec", "paused")
+	if paused {
+		t.Fatalf("expected patched object to have spec.paused=false")
+	}
+	replicas, _, _ := unstructured.NestedInt64(patchedUnstructured.Object, "spec", "replicas")
+	if replicas != 3 {
+		t.Fatalf("expected unrelated fields to survive the merge, got replicas=%d", replicas)
+	}
+
+	// The original must be untouched.
+	originalPaused, _, _ := unstructured.NestedBool(original.Object, "spec", "paused")
+	if !originalPaused {
+		t.Fatalf("applyMergePatchLocally must not mutate the original object")
+	}
+}
+
+// TestRolloutCompleteForObjectMatchesEventLoopCheck guards against
+// waitForRolloutComplete only ever evaluating completion inside the watch
+// event loop: rolloutCompleteForObject must report the same verdict for an
+// already-complete object as the loop would for an equivalent event, so a
+// caller can check before ever starting the watch.
+func TestRolloutCompleteForObjectMatchesEventLoopCheck(t *testing.T) {
+	obj := newTestUnstructured("Deployment", map[string]interface{}{
+		"observedGeneration": int64(2), "updatedReplicas": int64(3), "availableReplicas": int64(3),
+	}, map[string]interface{}{"replicas": int64(3)})
+
+	done, message, err := rolloutCompleteForObject(obj, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !done {
+		t.Fatalf("expected an already-complete deployment to report done, got message %q", message)
+	}
+}
+
+func TestPrintResumeDiffReportsAChange(t *testing.T) {
+	original := newTestUnstructured("Deployment", nil, map[string]interface{}{"paused": true})
+	patched := newTestUnstructured("Deployment", nil, map[string]interface{}{"paused": false})
+
+	var out bytes.Buffer
+	if err := printResumeDiff(original, patched, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Len() == 0 {
+		t.Fatalf("expected a non-empty diff")
+	}
+	if !strings.Contains(out.String(), "-  paused: true") || !strings.Contains(out.String(), "+  paused: false") {
+		t.Fatalf("expected a unified line diff with -/+ markers, got:\n%s", out.String())
+	}
+}
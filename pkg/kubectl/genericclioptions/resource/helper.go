@@ -0,0 +1,91 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+// Helper provides methods for retrieving or mutating a resource's state
+// over a RESTClient. It mirrors the subset of upstream kubectl's
+// resource.Helper that the rollout subcommands rely on.
+type Helper struct {
+	RESTClient RESTClient
+	Resource   string
+
+	NamespaceScoped bool
+
+	// ServerDryRun, when true, asks the API server to validate the request
+	// without persisting the result (DryRun: []string{"All"}).
+	ServerDryRun bool
+}
+
+// NewHelper returns a Helper for the resource described by mapping.
+func NewHelper(client RESTClient, mapping *meta.RESTMapping) *Helper {
+	return &Helper{
+		RESTClient:      client,
+		Resource:        mapping.Resource.Resource,
+		NamespaceScoped: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}
+}
+
+// DryRun returns a copy of m with ServerDryRun set to flag, for chaining:
+// helper := NewHelper(client, mapping).DryRun(true)
+func (m *Helper) DryRun(flag bool) *Helper {
+	cp := *m
+	cp.ServerDryRun = flag
+	return &cp
+}
+
+func (m *Helper) withDryRun(req *rest.Request) *rest.Request {
+	if m.ServerDryRun {
+		return req.Param("dryRun", metav1.DryRunAll)
+	}
+	return req
+}
+
+// Patch sends a patch of the given type to the named resource and returns
+// the server's response object. subresources, if given, routes the patch to
+// a subresource (e.g. "status") instead of the main resource.
+func (m *Helper) Patch(namespace, name string, pt types.PatchType, data []byte, subresources ...string) (runtime.Object, error) {
+	req := m.RESTClient.Patch(pt).
+		NamespaceIfScoped(namespace, m.NamespaceScoped).
+		Resource(m.Resource).
+		Name(name).
+		SubResource(subresources...).
+		Body(data)
+	return m.withDryRun(req).Do().Get()
+}
+
+// WatchSingle watches the named resource starting from resourceVersion.
+func (m *Helper) WatchSingle(namespace, name, resourceVersion string) (watch.Interface, error) {
+	return m.RESTClient.Get().
+		NamespaceIfScoped(namespace, m.NamespaceScoped).
+		Resource(m.Resource).
+		VersionedParams(&metav1.ListOptions{
+			ResourceVersion: resourceVersion,
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			Watch:           true,
+		}, metav1.ParameterCodec).
+		Watch()
+}
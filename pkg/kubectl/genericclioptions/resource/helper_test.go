@@ -0,0 +1,155 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+)
+
+func newTestMapping() *meta.RESTMapping {
+	return &meta.RESTMapping{
+		Resource:         schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Scope:            meta.RESTScopeNamespace,
+	}
+}
+
+// TestHelperPatchServerDryRunSetsQueryParam verifies that DryRun(true) asks
+// the API server for a dry-run patch by sending the dryRun=All query
+// parameter.
+func TestHelperPatchServerDryRunSetsQueryParam(t *testing.T) {
+	var gotQuery string
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         schema.GroupVersion{Group: "apps", Version: "v1"},
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			gotQuery = req.URL.RawQuery
+			body := ioutil.NopCloser(strings.NewReader(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx"},"spec":{"paused":false}}`))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       body,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}),
+	}
+
+	helper := NewHelper(client, newTestMapping()).DryRun(true)
+	if _, err := helper.Patch("default", "nginx", types.StrategicMergePatchType, []byte(`{"spec":{"paused":false}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(gotQuery, "dryRun=All") {
+		t.Fatalf("expected request query to contain dryRun=All, got %q", gotQuery)
+	}
+}
+
+// TestHelperPatchWithoutDryRunOmitsQueryParam is the control case: without
+// DryRun, the request must not carry the dryRun parameter.
+func TestHelperPatchWithoutDryRunOmitsQueryParam(t *testing.T) {
+	var gotQuery string
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         schema.GroupVersion{Group: "apps", Version: "v1"},
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			gotQuery = req.URL.RawQuery
+			body := ioutil.NopCloser(strings.NewReader(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx"}}`))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       body,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}),
+	}
+
+	helper := NewHelper(client, newTestMapping())
+	if _, err := helper.Patch("default", "nginx", types.StrategicMergePatchType, []byte(`{"spec":{"paused":false}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(gotQuery, "dryRun") {
+		t.Fatalf("expected no dryRun param without DryRun(true), got query %q", gotQuery)
+	}
+}
+
+// TestHelperPatchSendsRequestedPatchType verifies that Patch sets the
+// Content-Type header to whatever types.PatchType was asked for, rather than
+// always sending a strategic merge patch, so a caller targeting a CRD that
+// rejects strategic merge actually gets the patch type it requested.
+func TestHelperPatchSendsRequestedPatchType(t *testing.T) {
+	var gotContentType string
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         schema.GroupVersion{Group: "rollouts.kruise.io", Version: "v1alpha1"},
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			gotContentType = req.Header.Get("Content-Type")
+			body := ioutil.NopCloser(strings.NewReader(`{"apiVersion":"rollouts.kruise.io/v1alpha1","kind":"Rollout","metadata":{"name":"nginx"}}`))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       body,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}),
+	}
+
+	helper := NewHelper(client, newTestMapping())
+	if _, err := helper.Patch("default", "nginx", types.MergePatchType, []byte(`{"spec":{"paused":false}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotContentType != string(types.MergePatchType) {
+		t.Fatalf("got Content-Type %q, want %q", gotContentType, types.MergePatchType)
+	}
+}
+
+// TestHelperPatchRoutesToSubresource verifies that Patch, given a
+// subresource, sends the request to .../<name>/<subresource> instead of the
+// main resource endpoint, so that fields living under a subresource (e.g.
+// status) aren't silently dropped by being sent to the wrong endpoint.
+func TestHelperPatchRoutesToSubresource(t *testing.T) {
+	var gotPath string
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         schema.GroupVersion{Group: "rollouts.kruise.io", Version: "v1alpha1"},
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			gotPath = req.URL.Path
+			body := ioutil.NopCloser(strings.NewReader(`{"apiVersion":"rollouts.kruise.io/v1alpha1","kind":"Rollout","metadata":{"name":"nginx"}}`))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       body,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}),
+	}
+
+	helper := NewHelper(client, newTestMapping())
+	if _, err := helper.Patch("default", "nginx", types.MergePatchType, []byte(`{"status":{"currentStepIndex":1}}`), "status"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasSuffix(gotPath, "/nginx/status") {
+		t.Fatalf("expected request path to end in /nginx/status, got %q", gotPath)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+// RESTClient is the subset of a REST client that Helper needs to issue
+// requests for a single resource.
+type RESTClient interface {
+	Get() *rest.Request
+	Post() *rest.Request
+	Patch(pt types.PatchType) *rest.Request
+	Delete() *rest.Request
+	Put() *rest.Request
+}
+
+// Info contains temporary info to execute a REST call, or show the results
+// of an already completed call. It mirrors the subset of upstream
+// kubectl's resource.Info that this package's callers rely on.
+type Info struct {
+	Client  RESTClient
+	Mapping *meta.RESTMapping
+
+	Namespace string
+	Name      string
+
+	// ResourceVersion is set from Object's metadata once it is populated.
+	ResourceVersion string
+
+	Object runtime.Object
+}
+
+// Refresh updates the object with a new object. When force is true, Object
+// is replaced even if obj's metadata cannot be read; ResourceVersion is then
+// left unchanged and no error is returned.
+func (i *Info) Refresh(obj runtime.Object, force bool) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		if !force {
+			return err
+		}
+		i.Object = obj
+		return nil
+	}
+	i.Object = obj
+	i.ResourceVersion = accessor.GetResourceVersion()
+	return nil
+}
+
+// FilenameOptions holds the flag values for -f/--filename and -R/--recursive.
+type FilenameOptions struct {
+	Filenames []string
+	Recursive bool
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymorphichelpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+)
+
+// ApprovePatchType returns the patch type, and subresource if any, that
+// RunApprove must target for obj's kind. Kruise and Argo Rollouts are CRDs
+// with no patch-strategy metadata, so a strategic merge patch is rejected
+// for them; a Kruise Rollout's currentStepIndex additionally lives under
+// status, a separate subresource from the main resource PATCH, so writing
+// it through the main endpoint would be silently dropped.
+func ApprovePatchType(obj runtime.Object) (pt types.PatchType, subresource string, err error) {
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return "", "", err
+	}
+	switch gvkKey(u.GroupVersionKind()) {
+	case "rollouts.kruise.io/Rollout":
+		return types.MergePatchType, "status", nil
+	case "argoproj.io/Rollout":
+		return types.MergePatchType, "", nil
+	default:
+		return types.StrategicMergePatchType, "", nil
+	}
+}
+
+// ObjectApproverFn advances a workload that is gated at a canary or
+// blue-green rollout step. batch < 0 means "advance to the next pending
+// batch"; all means "approve every remaining batch". Only Kruise Rollouts
+// act on batch and all; Deployments and Argo Rollouts ignore both and are
+// only unpaused.
+func ObjectApproverFn(info *resource.Info, batch int, all bool) ([]byte, error) {
+	obj, err := toUnstructured(info.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := obj.GroupVersionKind()
+	switch gvkKey(gvk) {
+	case "apps/Deployment":
+		// Built-in Deployments have no gated-step concept; approving one
+		// is equivalent to resuming it.
+		return resumeDeployment(obj, false)
+	case "rollouts.kruise.io/Rollout":
+		return approveKruiseRollout(obj, batch, all)
+	case "argoproj.io/Rollout":
+		return approveArgoRollout(obj, batch, all)
+	default:
+		return nil, fmt.Errorf("no approver has been implemented for %q", gvk)
+	}
+}
+
+// approveKruiseRollout advances a Kruise Rollout's batch release by
+// patching status.currentStepIndex.
+func approveKruiseRollout(obj *unstructured.Unstructured, batch int, all bool) ([]byte, error) {
+	currentStep, _, err := unstructured.NestedInt64(obj.Object, "status", "currentStepIndex")
+	if err != nil {
+		return nil, err
+	}
+
+	var nextStep int64
+	switch {
+	case all:
+		steps, found, err := unstructured.NestedSlice(obj.Object, "spec", "strategy", "canary", "steps")
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return []byte("{}"), nil
+		}
+		nextStep = int64(len(steps))
+	case batch >= 0:
+		nextStep = int64(batch)
+	default:
+		nextStep = currentStep + 1
+	}
+
+	if nextStep == currentStep {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"currentStepIndex": nextStep},
+	})
+}
+
+// approveArgoRollout clears an Argo Rollout's spec.paused field, letting it
+// proceed to its next step.
+func approveArgoRollout(obj *unstructured.Unstructured, batch int, all bool) ([]byte, error) {
+	paused, found, err := unstructured.NestedBool(obj.Object, "spec", "paused")
+	if err != nil {
+		return nil, err
+	}
+	if !found || !paused {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"paused": false},
+	})
+}
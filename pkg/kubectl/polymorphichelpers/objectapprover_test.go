@@ -0,0 +1,173 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymorphichelpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+)
+
+func TestObjectApproverFn(t *testing.T) {
+	tests := []struct {
+		name      string
+		group     string
+		kind      string
+		spec      map[string]interface{}
+		batch     int
+		all       bool
+		wantNoop  bool
+		wantErr   bool
+		wantField int64
+	}{
+		{
+			name:     "paused deployment approves as a resume",
+			group:    "apps",
+			kind:     "Deployment",
+			spec:     map[string]interface{}{"paused": true},
+			batch:    -1,
+			wantNoop: false,
+		},
+		{
+			name: "kruise rollout with no pending gate is a no-op",
+			group: "rollouts.kruise.io",
+			kind:  "Rollout",
+			spec: map[string]interface{}{
+				"strategy": map[string]interface{}{
+					"canary": map[string]interface{}{"steps": []interface{}{map[string]interface{}{}}},
+				},
+			},
+			batch:    0,
+			wantNoop: true,
+		},
+		{
+			name: "kruise rollout advances to the requested batch",
+			group: "rollouts.kruise.io",
+			kind:  "Rollout",
+			spec: map[string]interface{}{
+				"strategy": map[string]interface{}{
+					"canary": map[string]interface{}{"steps": []interface{}{map[string]interface{}{}, map[string]interface{}{}}},
+				},
+			},
+			batch:    1,
+			wantNoop: false,
+		},
+		{
+			name: "kruise rollout approves all remaining batches",
+			group: "rollouts.kruise.io",
+			kind:  "Rollout",
+			spec: map[string]interface{}{
+				"strategy": map[string]interface{}{
+					"canary": map[string]interface{}{"steps": []interface{}{map[string]interface{}{}, map[string]interface{}{}}},
+				},
+			},
+			batch:    -1,
+			all:      true,
+			wantNoop: false,
+		},
+		{
+			name:     "paused argo rollout is approved",
+			group:    "argoproj.io",
+			kind:     "Rollout",
+			spec:     map[string]interface{}{"paused": true},
+			batch:    -1,
+			wantNoop: false,
+		},
+		{
+			name:     "unregistered kind returns an error",
+			group:    "example.com",
+			kind:     "Widget",
+			spec:     map[string]interface{}{},
+			batch:    -1,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &resource.Info{Object: newUnstructured(tt.group, tt.kind, tt.spec)}
+			patch, err := ObjectApproverFn(info, tt.batch, tt.all)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got patch %q", patch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			isNoop := string(patch) == "{}"
+			if isNoop != tt.wantNoop {
+				t.Fatalf("got patch %q, wantNoop=%v", patch, tt.wantNoop)
+			}
+			if !isNoop {
+				var decoded map[string]interface{}
+				if err := json.Unmarshal(patch, &decoded); err != nil {
+					t.Fatalf("patch is not valid JSON: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestApprovePatchType(t *testing.T) {
+	tests := []struct {
+		name            string
+		group           string
+		kind            string
+		wantPatchType   types.PatchType
+		wantSubresource string
+	}{
+		{
+			name:          "built-in deployment uses strategic merge",
+			group:         "apps",
+			kind:          "Deployment",
+			wantPatchType: types.StrategicMergePatchType,
+		},
+		{
+			name:            "kruise rollout is a CRD and patches status as a subresource",
+			group:           "rollouts.kruise.io",
+			kind:            "Rollout",
+			wantPatchType:   types.MergePatchType,
+			wantSubresource: "status",
+		},
+		{
+			name:          "argo rollout is a CRD and requires a merge patch",
+			group:         "argoproj.io",
+			kind:          "Rollout",
+			wantPatchType: types.MergePatchType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newUnstructured(tt.group, tt.kind, nil)
+			pt, subresource, err := ApprovePatchType(obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pt != tt.wantPatchType {
+				t.Fatalf("got patch type %q, want %q", pt, tt.wantPatchType)
+			}
+			if subresource != tt.wantSubresource {
+				t.Fatalf("got subresource %q, want %q", subresource, tt.wantSubresource)
+			}
+		})
+	}
+}
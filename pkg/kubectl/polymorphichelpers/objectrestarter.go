@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymorphichelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+)
+
+// restartableKinds are the pod-template-carrying workloads a rolling
+// restart is meaningful for.
+var restartableKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// ObjectRestarterFn returns a strategic-merge patch that sets the
+// kubectl.kubernetes.io/restartedAt annotation on obj's pod template to the
+// current time, which triggers a rolling restart without any other
+// manifest change.
+func ObjectRestarterFn(info *resource.Info) ([]byte, error) {
+	obj, err := toUnstructured(info.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	if !restartableKinds[obj.GetKind()] {
+		return nil, fmt.Errorf("no restarter has been implemented for %q", obj.GroupVersionKind())
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{
+						"kubectl.kubernetes.io/restartedAt": time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymorphichelpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+)
+
+func TestObjectRestarterFn(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		wantErr bool
+	}{
+		{name: "deployment can be restarted", kind: "Deployment"},
+		{name: "statefulset can be restarted", kind: "StatefulSet"},
+		{name: "daemonset can be restarted", kind: "DaemonSet"},
+		{name: "unsupported kind errors", kind: "Job", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &resource.Info{Object: newUnstructured("apps", tt.kind, nil)}
+			patch, err := ObjectRestarterFn(info)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got patch %q", patch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var decoded struct {
+				Spec struct {
+					Template struct {
+						Metadata struct {
+							Annotations map[string]string `json:"annotations"`
+						} `json:"metadata"`
+					} `json:"template"`
+				} `json:"spec"`
+			}
+			if err := json.Unmarshal(patch, &decoded); err != nil {
+				t.Fatalf("patch is not valid JSON: %v", err)
+			}
+			if _, ok := decoded.Spec.Template.Metadata.Annotations["kubectl.kubernetes.io/restartedAt"]; !ok {
+				t.Fatalf("expected restartedAt annotation in patch, got %q", patch)
+			}
+		})
+	}
+}
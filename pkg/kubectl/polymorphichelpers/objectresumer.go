@@ -0,0 +1,213 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymorphichelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+)
+
+// mergePatchKinds holds the GVKs whose resume patch must be sent as a JSON
+// merge patch rather than a strategic merge patch: they are CRDs with no
+// patch-strategy metadata registered, so the API server rejects a
+// strategic-merge Content-Type for them outright.
+var mergePatchKinds = map[string]bool{
+	"apps.kruise.io/CloneSet":    true,
+	"apps.kruise.io/StatefulSet": true,
+}
+
+// ResumePatchType returns the patch type RunResume must use to apply a
+// resumer's patch to obj. Built-in workloads (Deployment, StatefulSet,
+// DaemonSet) accept a strategic merge patch; the Kruise CRDs do not.
+func ResumePatchType(obj runtime.Object) (types.PatchType, error) {
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return "", err
+	}
+	if mergePatchKinds[gvkKey(u.GroupVersionKind())] {
+		return types.MergePatchType, nil
+	}
+	return types.StrategicMergePatchType, nil
+}
+
+// resumePatchFn builds the patch that resumes a paused
+// rollout of a particular kind. full additionally clears any
+// partition-based hold.
+type resumePatchFn func(obj *unstructured.Unstructured, full bool) ([]byte, error)
+
+// resumersByGVK is keyed by "<group>/<kind>" so that kinds which share a
+// name across groups (e.g. Kruise's Advanced StatefulSet is also called
+// "StatefulSet") don't collide with their built-in namesakes.
+var resumersByGVK = map[string]resumePatchFn{
+	"apps/Deployment":            resumeDeployment,
+	"apps/StatefulSet":           resumeStatefulSet,
+	"apps/DaemonSet":             resumeDaemonSet,
+	"apps.kruise.io/CloneSet":    resumeKruisePaused,
+	"apps.kruise.io/StatefulSet": resumeKruisePaused,
+}
+
+// ObjectResumerFn resumes a paused object by returning the patch to apply
+// to it; ResumePatchType gives the patch type to send it with (strategic
+// merge for built-in kinds, JSON merge for the Kruise CRDs). It dispatches
+// on the object's GroupVersionKind so
+// that new workload kinds can be plugged in by adding an entry to
+// resumersByGVK.
+func ObjectResumerFn(info *resource.Info, full bool) ([]byte, error) {
+	obj, err := toUnstructured(info.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := obj.GroupVersionKind()
+	fn, ok := resumersByGVK[gvkKey(gvk)]
+	if !ok {
+		return nil, fmt.Errorf("no resumer has been implemented for %q", gvk)
+	}
+	return fn(obj, full)
+}
+
+func gvkKey(gvk schema.GroupVersionKind) string {
+	return gvk.Group + "/" + gvk.Kind
+}
+
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+func resumeDeployment(obj *unstructured.Unstructured, full bool) ([]byte, error) {
+	paused, found, err := unstructured.NestedBool(obj.Object, "spec", "paused")
+	if err != nil {
+		return nil, err
+	}
+	if !found || !paused {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"paused": false},
+	})
+}
+
+func resumeStatefulSet(obj *unstructured.Unstructured, full bool) ([]byte, error) {
+	paused, _, err := unstructured.NestedBool(obj.Object, "spec", "updateStrategy", "rollingUpdate", "paused")
+	if err != nil {
+		return nil, err
+	}
+	partition, partitionFound, err := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	if err != nil {
+		return nil, err
+	}
+
+	rollingUpdate := map[string]interface{}{}
+	if paused {
+		rollingUpdate["paused"] = false
+	}
+	if full && partitionFound && partition != 0 {
+		rollingUpdate["partition"] = int64(0)
+	}
+	if len(rollingUpdate) == 0 {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"updateStrategy": map[string]interface{}{"rollingUpdate": rollingUpdate},
+		},
+	})
+}
+
+// resumeDaemonSet lifts a maxUnavailable: 0 hold, which is the common
+// convention for pausing a DaemonSet rollout since DaemonSets have no
+// native pause field. The pre-pause value isn't recorded anywhere a
+// resumer can read it back, so resuming always sets maxUnavailable to 1
+// (the same conservative default the DaemonSet API itself uses) rather
+// than guessing at what the caller had before.
+func resumeDaemonSet(obj *unstructured.Unstructured, full bool) ([]byte, error) {
+	maxUnavailable, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", "updateStrategy", "rollingUpdate", "maxUnavailable")
+	if err != nil {
+		return nil, err
+	}
+	if !found || !isZero(maxUnavailable) {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"updateStrategy": map[string]interface{}{
+				"rollingUpdate": map[string]interface{}{"maxUnavailable": 1},
+			},
+		},
+	})
+}
+
+// resumeKruisePaused clears spec.updateStrategy.paused, the shared
+// convention used by both the Kruise CloneSet and Advanced StatefulSet CRDs.
+func resumeKruisePaused(obj *unstructured.Unstructured, full bool) ([]byte, error) {
+	paused, found, err := unstructured.NestedBool(obj.Object, "spec", "updateStrategy", "paused")
+	if err != nil {
+		return nil, err
+	}
+	if !found || !paused {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"updateStrategy": map[string]interface{}{"paused": false},
+		},
+	})
+}
+
+// isZero reports whether v, an intstr.IntOrString field read off an
+// unstructured object, encodes a zero value. Numbers decode as int64 when
+// converted from a typed object but as float64 when decoded from raw JSON
+// (e.g. an informer watch event), and percentages may carry whitespace or a
+// leading sign, so all of those forms are normalized before comparing.
+func isZero(v interface{}) bool {
+	switch t := v.(type) {
+	case int:
+		return t == 0
+	case int32:
+		return t == 0
+	case int64:
+		return t == 0
+	case float32:
+		return t == 0
+	case float64:
+		return t == 0
+	case json.Number:
+		f, err := t.Float64()
+		return err == nil && f == 0
+	case string:
+		s := strings.TrimSuffix(strings.TrimSpace(t), "%")
+		n, err := strconv.ParseFloat(s, 64)
+		return err == nil && n == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymorphichelpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/genericclioptions/resource"
+)
+
+func newUnstructured(group, kind string, spec map[string]interface{}) *unstructured.Unstructured {
+	apiVersion := "v1"
+	if group != "" {
+		apiVersion = group + "/v1"
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "test"},
+		"spec":       spec,
+	}}
+}
+
+func TestObjectResumerFn(t *testing.T) {
+	tests := []struct {
+		name      string
+		group     string
+		kind      string
+		spec      map[string]interface{}
+		full      bool
+		wantNoop  bool
+		wantErr   bool
+		wantField string
+	}{
+		{
+			name:     "paused deployment is resumed",
+			kind:     "Deployment",
+			group:    "apps",
+			spec:     map[string]interface{}{"paused": true},
+			wantNoop: false,
+		},
+		{
+			name:     "already running deployment is a no-op",
+			kind:     "Deployment",
+			group:    "apps",
+			spec:     map[string]interface{}{"paused": false},
+			wantNoop: true,
+		},
+		{
+			name: "paused statefulset clears rollingUpdate.paused only",
+			kind: "StatefulSet",
+			group: "apps",
+			spec: map[string]interface{}{
+				"updateStrategy": map[string]interface{}{
+					"rollingUpdate": map[string]interface{}{"paused": true, "partition": int64(2)},
+				},
+			},
+			full:     false,
+			wantNoop: false,
+		},
+		{
+			name: "full resume of partitioned statefulset clears the partition too",
+			kind: "StatefulSet",
+			group: "apps",
+			spec: map[string]interface{}{
+				"updateStrategy": map[string]interface{}{
+					"rollingUpdate": map[string]interface{}{"paused": false, "partition": int64(2)},
+				},
+			},
+			full:     true,
+			wantNoop: false,
+		},
+		{
+			name: "daemonset with a maxUnavailable hold is resumed",
+			kind: "DaemonSet",
+			group: "apps",
+			spec: map[string]interface{}{
+				"updateStrategy": map[string]interface{}{
+					"rollingUpdate": map[string]interface{}{"maxUnavailable": int64(0)},
+				},
+			},
+			wantNoop: false,
+		},
+		{
+			name: "kruise clonesets resume via updateStrategy.paused",
+			kind: "CloneSet",
+			group: "apps.kruise.io",
+			spec: map[string]interface{}{
+				"updateStrategy": map[string]interface{}{"paused": true},
+			},
+			wantNoop: false,
+		},
+		{
+			name: "kruise advanced statefulset resumes via updateStrategy.paused",
+			kind: "StatefulSet",
+			group: "apps.kruise.io",
+			spec: map[string]interface{}{
+				"updateStrategy": map[string]interface{}{"paused": true},
+			},
+			wantNoop: false,
+		},
+		{
+			name:    "unregistered kind returns an error",
+			kind:    "Rollout",
+			group:   "argoproj.io",
+			spec:    map[string]interface{}{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &resource.Info{Object: newUnstructured(tt.group, tt.kind, tt.spec)}
+			patch, err := ObjectResumerFn(info, tt.full)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got patch %q", patch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			isNoop := string(patch) == "{}"
+			if isNoop != tt.wantNoop {
+				t.Fatalf("got patch %q, wantNoop=%v", patch, tt.wantNoop)
+			}
+			if !isNoop {
+				var decoded map[string]interface{}
+				if err := json.Unmarshal(patch, &decoded); err != nil {
+					t.Fatalf("patch is not valid JSON: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestResumePatchType(t *testing.T) {
+	tests := []struct {
+		name  string
+		group string
+		kind  string
+		want  types.PatchType
+	}{
+		{name: "built-in deployment uses strategic merge", group: "apps", kind: "Deployment", want: types.StrategicMergePatchType},
+		{name: "built-in statefulset uses strategic merge", group: "apps", kind: "StatefulSet", want: types.StrategicMergePatchType},
+		{name: "built-in daemonset uses strategic merge", group: "apps", kind: "DaemonSet", want: types.StrategicMergePatchType},
+		{name: "kruise cloneset is a CRD and requires a merge patch", group: "apps.kruise.io", kind: "CloneSet", want: types.MergePatchType},
+		{name: "kruise advanced statefulset is a CRD and requires a merge patch", group: "apps.kruise.io", kind: "StatefulSet", want: types.MergePatchType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newUnstructured(tt.group, tt.kind, nil)
+			got, err := ResumePatchType(obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got patch type %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeStatefulSetFullClearsPartitionOnly(t *testing.T) {
+	obj := newUnstructured("apps", "StatefulSet", map[string]interface{}{
+		"updateStrategy": map[string]interface{}{
+			"rollingUpdate": map[string]interface{}{"paused": false, "partition": int64(3)},
+		},
+	})
+
+	patch, err := resumeStatefulSet(obj, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Spec struct {
+			UpdateStrategy struct {
+				RollingUpdate struct {
+					Paused    *bool `json:"paused"`
+					Partition *int  `json:"partition"`
+				} `json:"rollingUpdate"`
+			} `json:"updateStrategy"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch, &decoded); err != nil {
+		t.Fatalf("patch is not valid JSON: %v", err)
+	}
+	if decoded.Spec.UpdateStrategy.RollingUpdate.Paused != nil {
+		t.Fatalf("expected paused to be omitted since it was already false, got %v", *decoded.Spec.UpdateStrategy.RollingUpdate.Paused)
+	}
+	if decoded.Spec.UpdateStrategy.RollingUpdate.Partition == nil || *decoded.Spec.UpdateStrategy.RollingUpdate.Partition != 0 {
+		t.Fatalf("expected partition to be cleared to 0, got %v", decoded.Spec.UpdateStrategy.RollingUpdate.Partition)
+	}
+}